@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"math/rand"
 	"os"
 	"path"
 	"strings"
@@ -26,8 +27,86 @@ const (
 
 	// minRevokeDelay is used to prevent an instant revoke on restore
 	minRevokeDelay = 5 * time.Second
+
+	// defaultLeaseJitter bounds the random jitter added to a lease's
+	// expiration timer. Without this, leases restored at boot with
+	// identical (or near-identical) expirations all fire at once and
+	// hammer the backends with revocations.
+	defaultLeaseJitter = 30 * time.Second
+
+	// revokeQueueSubPath is the sub-path (under the expiration view) used
+	// to durably persist revocations that failed and are awaiting retry.
+	revokeQueueSubPath = "revoke/"
+
+	// revokeQueueInterval is how often the revocation queue worker wakes
+	// up to look for due entries.
+	revokeQueueInterval = 5 * time.Second
 )
 
+// ExpirationConfig is used to tune the behavior of an ExpirationManager.
+// A nil *ExpirationConfig (or a zero-value field within one) falls back
+// to the package defaults.
+//
+// Known-unwired scaffolding: setupExpiration always calls
+// NewExpirationManager with a nil config today, so none of these fields
+// are reachable by an operator yet. See the TODO on setupExpiration.
+type ExpirationConfig struct {
+	// RevokeRetryBase is the baseline delay that revokeBackoff doubles
+	// per attempt when scheduling retries of queued revocations.
+	// Defaults to revokeRetryBase.
+	RevokeRetryBase time.Duration
+
+	// MaxRevokeAttempts caps the backoff exponent used by revokeBackoff.
+	// It does not limit the number of attempts: a queued revocation is
+	// retried until it succeeds. Defaults to maxRevokeAttempts.
+	MaxRevokeAttempts uint
+
+	// LeaseJitter bounds the random jitter added to the timer set for a
+	// lease's expiration. Defaults to defaultLeaseJitter. Set to a
+	// negative value to disable jitter entirely.
+	LeaseJitter time.Duration
+
+	// Cipher wraps every persisted lease entry in an extra layer of
+	// envelope encryption, on top of the outer barrier. Defaults to a
+	// no-op EntryCipher, leaving entries protected by the barrier alone,
+	// exactly as before EntryCipher existed.
+	Cipher EntryCipher
+}
+
+// DefaultExpirationConfig returns the configuration used when
+// NewExpirationManager is given a nil config.
+func DefaultExpirationConfig() *ExpirationConfig {
+	return &ExpirationConfig{
+		RevokeRetryBase:   revokeRetryBase,
+		MaxRevokeAttempts: maxRevokeAttempts,
+		LeaseJitter:       defaultLeaseJitter,
+		Cipher:            noopEntryCipher{},
+	}
+}
+
+// normalizedExpirationConfig merges a possibly-nil or partially-set
+// config with the package defaults.
+func normalizedExpirationConfig(config *ExpirationConfig) *ExpirationConfig {
+	def := DefaultExpirationConfig()
+	if config == nil {
+		return def
+	}
+	out := *config
+	if out.RevokeRetryBase == 0 {
+		out.RevokeRetryBase = def.RevokeRetryBase
+	}
+	if out.MaxRevokeAttempts == 0 {
+		out.MaxRevokeAttempts = def.MaxRevokeAttempts
+	}
+	if out.LeaseJitter == 0 {
+		out.LeaseJitter = def.LeaseJitter
+	}
+	if out.Cipher == nil {
+		out.Cipher = noopEntryCipher{}
+	}
+	return &out
+}
+
 // ExpirationManager is used by the Core to manage leases. Secrets
 // can provide a lease, meaning that they can be renewed or revoked.
 // If a secret is not renewed in timely manner, it may be expired, and
@@ -37,14 +116,24 @@ type ExpirationManager struct {
 	view       *BarrierView
 	tokenStore *TokenStore
 	logger     *log.Logger
+	config     *ExpirationConfig
 
 	pending     map[string]*time.Timer
 	pendingLock sync.Mutex
+
+	renewers     map[string]*LeaseRenewer
+	renewersLock sync.Mutex
+
+	revokeView   *BarrierView
+	revokeStopCh chan struct{}
+	revokeWG     sync.WaitGroup
+	revokeLock   sync.Mutex
 }
 
 // NewExpirationManager creates a new ExpirationManager that is backed
 // using a given view, and uses the provided router for revocation.
-func NewExpirationManager(router *Router, view *BarrierView, ts *TokenStore, logger *log.Logger) *ExpirationManager {
+// A nil config uses DefaultExpirationConfig.
+func NewExpirationManager(router *Router, view *BarrierView, ts *TokenStore, logger *log.Logger, config *ExpirationConfig) *ExpirationManager {
 	if logger == nil {
 		logger = log.New(os.Stderr, "", log.LstdFlags)
 	}
@@ -53,31 +142,92 @@ func NewExpirationManager(router *Router, view *BarrierView, ts *TokenStore, log
 		view:       view,
 		tokenStore: ts,
 		logger:     logger,
+		config:     normalizedExpirationConfig(config),
 		pending:    make(map[string]*time.Timer),
+		renewers:   make(map[string]*LeaseRenewer),
+		revokeView: view.SubView(revokeQueueSubPath),
 	}
 	return exp
 }
 
+// trackRenewer registers a LeaseRenewer so that its lifecycle can be
+// tied to the lease it manages: Stop cancels all tracked renewers, and
+// Revoke cancels the one (if any) for the revoked vaultID.
+func (m *ExpirationManager) trackRenewer(r *LeaseRenewer) {
+	m.renewersLock.Lock()
+	defer m.renewersLock.Unlock()
+	if old, ok := m.renewers[r.vaultID]; ok {
+		old.Stop()
+	}
+	m.renewers[r.vaultID] = r
+}
+
+// untrackRenewer removes a completed renewer from the tracked set. A
+// newer renewer that has since replaced it for the same vaultID is left
+// tracked.
+func (m *ExpirationManager) untrackRenewer(r *LeaseRenewer) {
+	m.renewersLock.Lock()
+	defer m.renewersLock.Unlock()
+	if m.renewers[r.vaultID] == r {
+		delete(m.renewers, r.vaultID)
+	}
+}
+
+// stopRenewer stops and untracks the renewer (if any) for vaultID.
+func (m *ExpirationManager) stopRenewer(vaultID string) {
+	m.renewersLock.Lock()
+	r, ok := m.renewers[vaultID]
+	if ok {
+		delete(m.renewers, vaultID)
+	}
+	m.renewersLock.Unlock()
+	if ok {
+		r.Stop()
+	}
+}
+
+// jitter applies a bounded random amount of jitter to d, to spread out
+// timers that would otherwise fire in lockstep (e.g. a batch of leases
+// restored at boot with the same expiration). The result is never
+// shorter than d.
+func (m *ExpirationManager) jitter(d time.Duration) time.Duration {
+	if m.config.LeaseJitter <= 0 {
+		return d
+	}
+	return d + time.Duration(rand.Int63n(int64(m.config.LeaseJitter)))
+}
+
 // setupExpiration is invoked after we've loaded the mount table to
-// initialize the expiration manager
+// initialize the expiration manager.
+//
+// TODO: this always passes a nil ExpirationConfig, so DefaultExpirationConfig
+// is all any operator gets today; there is no config surface on Core yet
+// for LeaseJitter/RevokeRetryBase/MaxRevokeAttempts/Cipher tuning. Thread
+// an ExpirationConfig through from Core's own configuration once that
+// exists.
 func (c *Core) setupExpiration() error {
 	// Create a sub-view
 	view := c.systemView.SubView(expirationSubPath)
 
 	// Create the manager
-	mgr := NewExpirationManager(c.router, view, c.tokenStore, c.logger)
+	mgr := NewExpirationManager(c.router, view, c.tokenStore, c.logger, nil)
 	c.expiration = mgr
 
 	// Restore the existing state
 	if err := c.expiration.Restore(); err != nil {
 		return fmt.Errorf("expiration state restore failed: %v", err)
 	}
+
+	// Start draining any revocations that failed and were durably queued
+	// for retry
+	c.expiration.startRevocationWorker()
 	return nil
 }
 
 // stopExpiration is used to stop the expiration manager before
 // sealing the Vault.
 func (c *Core) stopExpiration() error {
+	c.expiration.stopRevocationWorker()
 	if err := c.expiration.Stop(); err != nil {
 		return err
 	}
@@ -99,8 +249,21 @@ func (m *ExpirationManager) Restore() error {
 
 	// Restore each key
 	for _, vaultID := range existing {
+		// The durable revocation queue lives under revokeQueueSubPath,
+		// nested inside this same view's keyspace, so CollectKeys
+		// surfaces its entries alongside real lease keys. Those are
+		// revocationEntry records, not leaseEntry records (and happen
+		// to carry their own "vault_id" field, naming the lease they're
+		// revoking) — skip them here rather than feeding them to
+		// loadEntryEnvelope/persistEntry, which would otherwise restore
+		// bogus leases and, once re-wrapping is live, overwrite the
+		// real lease's stored entry with a blank one.
+		if strings.HasPrefix(vaultID, revokeQueueSubPath) {
+			continue
+		}
+
 		// Load the entry
-		le, err := m.loadEntry(vaultID)
+		le, keyVersion, err := m.loadEntryEnvelope(vaultID)
 		if err != nil {
 			return err
 		}
@@ -110,25 +273,54 @@ func (m *ExpirationManager) Restore() error {
 			continue
 		}
 
+		// If the entry was sealed under a key version other than the
+		// EntryCipher's current one, re-wrap it now
+		if current := m.entryCipherKeyVersion(); keyVersion != current {
+			if err := m.persistEntry(le); err != nil {
+				return fmt.Errorf("failed to re-wrap lease '%s': %v", vaultID, err)
+			}
+			m.logger.Printf("[INFO] expire: re-wrapped lease '%s' under key version %d", vaultID, current)
+		}
+
 		// If there is no expiry time, don't do anything
 		if le.ExpireTime.IsZero() {
 			continue
 		}
 
+		// If a revocation for this lease is already durably queued, it
+		// is already in-flight: leave it for the revocation worker
+		// rather than re-expiring it and resetting its retry state
+		re, err := m.loadRevocation(le.VaultID)
+		if err != nil {
+			return err
+		}
+		if re != nil {
+			continue
+		}
+
 		// Determine the remaining time to expiration
 		expires := le.ExpireTime.Sub(time.Now().UTC())
 		if expires <= 0 {
 			expires = minRevokeDelay
 		}
 
-		// Setup revocation timer
-		m.pending[le.VaultID] = time.AfterFunc(expires, func() {
+		// Setup revocation timer, with jitter so that a batch of leases
+		// restored with the same expiration don't all fire at once
+		m.pending[le.VaultID] = time.AfterFunc(m.jitter(expires), func() {
 			m.expireID(le.VaultID)
 		})
 	}
 	if len(m.pending) > 0 {
 		m.logger.Printf("[INFO] expire: restored %d leases", len(m.pending))
 	}
+
+	queued, err := m.listRevocations()
+	if err != nil {
+		return fmt.Errorf("failed to scan revocation queue: %v", err)
+	}
+	if len(queued) > 0 {
+		m.logger.Printf("[INFO] expire: restored %d queued revocations", len(queued))
+	}
 	return nil
 }
 
@@ -142,6 +334,14 @@ func (m *ExpirationManager) Stop() error {
 	}
 	m.pending = make(map[string]*time.Timer)
 	m.pendingLock.Unlock()
+
+	// Stop all the tracked lease renewers
+	m.renewersLock.Lock()
+	for _, r := range m.renewers {
+		r.Stop()
+	}
+	m.renewers = make(map[string]*LeaseRenewer)
+	m.renewersLock.Unlock()
 	return nil
 }
 
@@ -175,6 +375,9 @@ func (m *ExpirationManager) Revoke(vaultID string) error {
 		delete(m.pending, vaultID)
 	}
 	m.pendingLock.Unlock()
+
+	// Stop any renewer managing this lease; it has nothing left to renew
+	m.stopRenewer(vaultID)
 	return nil
 }
 
@@ -224,12 +427,26 @@ func (m *ExpirationManager) Renew(vaultID string, increment time.Duration) (*log
 		return nil, fmt.Errorf("lease expired")
 	}
 
+	// A periodic entry is never allowed to count down to a fixed
+	// expiry: every renewal, regardless of the caller-supplied
+	// increment, extends it to now + Period.
+	renewIncrement := increment
+	if le.Period > 0 {
+		renewIncrement = le.Period
+	}
+
 	// Attempt to renew the entry
-	resp, err := m.renewEntry(le, increment)
+	resp, err := m.renewEntry(le, renewIncrement)
 	if err != nil {
 		return nil, err
 	}
 
+	// Auth leases (logins) follow a distinct renewal path, since their
+	// lease accounting lives on *logical.Auth rather than *logical.Secret
+	if le.Auth != nil {
+		return m.renewAuthEntry(le, resp, renewIncrement)
+	}
+
 	// Fast-path if there is no lease
 	if resp == nil || resp.Secret == nil || resp.Secret.Lease == 0 {
 		return resp, nil
@@ -243,7 +460,9 @@ func (m *ExpirationManager) Renew(vaultID string, increment time.Duration) (*log
 	// Attach the VaultID
 	resp.Secret.VaultID = vaultID
 
-	// Update the lease entry
+	// Update the lease entry. Period is only ever set on auth entries,
+	// which are already routed to renewAuthEntry above, so this path
+	// only needs to account for Secret.Lease.
 	var expireTime time.Time
 	leaseTotal := resp.Secret.Lease + resp.Secret.LeaseGracePeriod
 	if resp.Secret.Lease > 0 {
@@ -252,6 +471,7 @@ func (m *ExpirationManager) Renew(vaultID string, increment time.Duration) (*log
 	le.Data = resp.Data
 	le.Secret = resp.Secret
 	le.ExpireTime = expireTime
+	le.LastRenewed = time.Now().UTC()
 	if err := m.persistEntry(le); err != nil {
 		return nil, err
 	}
@@ -259,7 +479,7 @@ func (m *ExpirationManager) Renew(vaultID string, increment time.Duration) (*log
 	// Update the expiration time
 	m.pendingLock.Lock()
 	if timer, ok := m.pending[vaultID]; ok {
-		timer.Reset(leaseTotal)
+		timer.Reset(m.jitter(leaseTotal))
 	}
 	m.pendingLock.Unlock()
 
@@ -267,6 +487,32 @@ func (m *ExpirationManager) Renew(vaultID string, increment time.Duration) (*log
 	return resp, nil
 }
 
+// renewAuthEntry finishes a renewal for an auth (login) lease: it
+// persists the refreshed *logical.Auth and sets ExpireTime to now + ttl,
+// where ttl is the renewIncrement that Renew already resolved to
+// le.Period for periodic entries.
+func (m *ExpirationManager) renewAuthEntry(le *leaseEntry, resp *logical.Response, ttl time.Duration) (*logical.Response, error) {
+	if resp == nil || resp.Auth == nil {
+		return nil, fmt.Errorf("no auth returned by renewal")
+	}
+
+	expireTime := time.Now().UTC().Add(ttl)
+	le.Auth = resp.Auth
+	le.ExpireTime = expireTime
+	le.LastRenewed = time.Now().UTC()
+	if err := m.persistEntry(le); err != nil {
+		return nil, err
+	}
+
+	m.pendingLock.Lock()
+	if timer, ok := m.pending[le.VaultID]; ok {
+		timer.Reset(m.jitter(ttl))
+	}
+	m.pendingLock.Unlock()
+
+	return resp, nil
+}
+
 // Register is used to take a request and response with an associated
 // lease. The secret gets assigned a vaultId and the management of
 // of lease is assumed by the expiration manager.
@@ -305,7 +551,7 @@ func (m *ExpirationManager) Register(req *logical.Request, resp *logical.Respons
 	// Setup revocation timer if there is a lease
 	if !expireTime.IsZero() {
 		m.pendingLock.Lock()
-		m.pending[le.VaultID] = time.AfterFunc(leaseTotal, func() {
+		m.pending[le.VaultID] = time.AfterFunc(m.jitter(leaseTotal), func() {
 			m.expireID(le.VaultID)
 		})
 		m.pendingLock.Unlock()
@@ -361,23 +607,307 @@ func (m *ExpirationManager) RegisterLogin(token string, req *credential.Request,
 }
 */
 
-// expireID is invoked when a given ID is expired
+// RegisterAuth is used to take a credential request and its resulting
+// auth and register the associated lease. This is the login analogue
+// of Register, distinct because auth lease accounting lives on
+// *logical.Auth rather than *logical.Secret, and because a login may
+// be periodic: when auth.Period is set, the token never counts down to
+// a fixed expiry, so revocation always runs through the token store
+// (and, on failure, the same durable revocation queue as any other
+// lease) rather than a backend's normal revoke path.
+//
+// Known-unwired scaffolding: nothing in this tree calls RegisterAuth
+// yet. It exists for credential backends' login paths (e.g. approle's
+// pathLoginUpdate) to call once their Core-facing plumbing is wired up
+// to invoke it instead of going through Register.
+func (m *ExpirationManager) RegisterAuth(token string, req *logical.Request, auth *logical.Auth) (string, error) {
+	if auth == nil {
+		return "", fmt.Errorf("cannot register nil auth")
+	}
+
+	ttl := auth.TTL
+	if auth.Period > 0 {
+		ttl = auth.Period
+	}
+	if ttl <= 0 {
+		return "", fmt.Errorf("auth has no TTL or Period")
+	}
+
+	now := time.Now().UTC()
+	le := leaseEntry{
+		VaultID:    path.Join(req.Path, generateUUID()),
+		LoginToken: token,
+		Path:       req.Path,
+		Auth:       auth,
+		Period:     auth.Period,
+		IssueTime:  now,
+		ExpireTime: now.Add(ttl),
+	}
+
+	// Encode the entry
+	if err := m.persistEntry(&le); err != nil {
+		return "", err
+	}
+
+	// Setup revocation timer
+	m.pendingLock.Lock()
+	m.pending[le.VaultID] = time.AfterFunc(m.jitter(ttl), func() {
+		m.expireID(le.VaultID)
+	})
+	m.pendingLock.Unlock()
+
+	// Done
+	return le.VaultID, nil
+}
+
+// expireID is invoked when a given ID is expired. It makes a single
+// revocation attempt; on failure, the lease is durably queued for
+// retry instead of blocking this goroutine in a retry loop, so a seal
+// or crash mid-retry doesn't drop the revocation and a permanently
+// broken backend doesn't tie up a goroutine for several minutes.
 func (m *ExpirationManager) expireID(vaultID string) {
 	// Clear from the pending expiration
 	m.pendingLock.Lock()
 	delete(m.pending, vaultID)
 	m.pendingLock.Unlock()
 
-	for attempt := uint(0); attempt < maxRevokeAttempts; attempt++ {
-		err := m.Revoke(vaultID)
-		if err == nil {
-			m.logger.Printf("[INFO] expire: revoked '%s'", vaultID)
+	if err := m.Revoke(vaultID); err != nil {
+		m.logger.Printf("[ERR] expire: failed to revoke '%s', queuing for retry: %v", vaultID, err)
+		m.enqueueRevocation(vaultID, 0, err)
+		return
+	}
+	m.logger.Printf("[INFO] expire: revoked '%s'", vaultID)
+}
+
+// revocationEntry is the durable record of a failed revocation that is
+// awaiting retry.
+type revocationEntry struct {
+	VaultID   string    `json:"vault_id"`
+	Attempt   uint      `json:"attempt"`
+	NextTry   time.Time `json:"next_try"`
+	LastError string    `json:"last_error"`
+}
+
+// RevocationStatusEntry describes a single lease stuck in the
+// revocation queue.
+type RevocationStatusEntry struct {
+	VaultID   string
+	Attempt   uint
+	NextTry   time.Time
+	LastError string
+}
+
+// RevocationStatus reports the depth of the durable revocation queue
+// and per-entry attempt counts, so operators can observe stuck
+// revocations rather than only seeing them go by in the logs.
+type RevocationStatus struct {
+	QueueDepth int
+	Entries    []RevocationStatusEntry
+}
+
+// RevocationStatus returns a point-in-time snapshot of the durable
+// revocation queue.
+func (m *ExpirationManager) RevocationStatus() (*RevocationStatus, error) {
+	entries, err := m.listRevocations()
+	if err != nil {
+		return nil, err
+	}
+	status := &RevocationStatus{
+		QueueDepth: len(entries),
+		Entries:    make([]RevocationStatusEntry, len(entries)),
+	}
+	for i, re := range entries {
+		status.Entries[i] = RevocationStatusEntry{
+			VaultID:   re.VaultID,
+			Attempt:   re.Attempt,
+			NextTry:   re.NextTry,
+			LastError: re.LastError,
+		}
+	}
+	return status, nil
+}
+
+// enqueueRevocation persists a revocationEntry for vaultID so the
+// revocation worker retries it on a schedule.
+func (m *ExpirationManager) enqueueRevocation(vaultID string, attempt uint, cause error) {
+	attempt++
+	re := &revocationEntry{
+		VaultID:   vaultID,
+		Attempt:   attempt,
+		NextTry:   time.Now().UTC().Add(m.revokeBackoff(attempt)),
+		LastError: cause.Error(),
+	}
+	if err := m.persistRevocation(re); err != nil {
+		m.logger.Printf("[ERR] expire: failed to queue revocation of '%s': %v", vaultID, err)
+	}
+}
+
+// revokeBackoff computes the jittered, exponential delay before the
+// next attempt for the given attempt count. The exponent is capped at
+// MaxRevokeAttempts so that a lease stuck far longer than that doesn't
+// back off indefinitely; it's still retried, just at a bounded rate.
+func (m *ExpirationManager) revokeBackoff(attempt uint) time.Duration {
+	exp := attempt
+	if exp > m.config.MaxRevokeAttempts {
+		exp = m.config.MaxRevokeAttempts
+	}
+	return m.jitter((1 << exp) * m.config.RevokeRetryBase)
+}
+
+// startRevocationWorker starts the background goroutine that drains
+// the durable revocation queue. It is a no-op if already running.
+func (m *ExpirationManager) startRevocationWorker() {
+	m.revokeLock.Lock()
+	defer m.revokeLock.Unlock()
+	if m.revokeStopCh != nil {
+		return
+	}
+	stopCh := make(chan struct{})
+	m.revokeStopCh = stopCh
+	m.revokeWG.Add(1)
+	go m.runRevocationWorker(stopCh)
+}
+
+// stopRevocationWorker stops the background revocation queue worker,
+// if running.
+func (m *ExpirationManager) stopRevocationWorker() {
+	m.revokeLock.Lock()
+	stopCh := m.revokeStopCh
+	m.revokeStopCh = nil
+	m.revokeLock.Unlock()
+	if stopCh == nil {
+		return
+	}
+	close(stopCh)
+	m.revokeWG.Wait()
+}
+
+// runRevocationWorker periodically drains the durable revocation queue
+// until told to stop.
+func (m *ExpirationManager) runRevocationWorker(stopCh chan struct{}) {
+	defer m.revokeWG.Done()
+	ticker := time.NewTicker(revokeQueueInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			m.drainRevocationQueue()
+		case <-stopCh:
 			return
 		}
-		m.logger.Printf("[ERR] expire: failed to revoke '%s': %v", vaultID, err)
-		time.Sleep((1 << attempt) * revokeRetryBase)
 	}
-	m.logger.Printf("[ERR] expire: maximum revoke attempts for '%s' reached", vaultID)
+}
+
+// drainRevocationQueue attempts revocation of every queued entry whose
+// NextTry has arrived.
+func (m *ExpirationManager) drainRevocationQueue() {
+	entries, err := m.listRevocations()
+	if err != nil {
+		m.logger.Printf("[ERR] expire: failed to scan revocation queue: %v", err)
+		return
+	}
+
+	now := time.Now().UTC()
+	for _, re := range entries {
+		if re.NextTry.After(now) {
+			continue
+		}
+		m.retryQueuedRevocation(re)
+	}
+}
+
+// retryQueuedRevocation makes one more revocation attempt for a queued
+// entry, re-queuing it with a fresh backoff on failure or clearing it
+// on success.
+func (m *ExpirationManager) retryQueuedRevocation(re *revocationEntry) {
+	le, err := m.loadEntry(re.VaultID)
+	if err != nil {
+		m.logger.Printf("[ERR] expire: failed to load queued revocation '%s': %v", re.VaultID, err)
+		return
+	}
+	if le == nil {
+		// Nothing left to revoke; drop the queue entry.
+		if err := m.deleteRevocation(re.VaultID); err != nil {
+			m.logger.Printf("[ERR] expire: failed to clear revocation queue entry for '%s': %v", re.VaultID, err)
+		}
+		return
+	}
+
+	if err := m.revokeEntry(le); err != nil {
+		m.logger.Printf("[ERR] expire: queued revoke of '%s' failed (attempt %d): %v", re.VaultID, re.Attempt+1, err)
+		m.enqueueRevocation(re.VaultID, re.Attempt, err)
+		return
+	}
+
+	if err := m.deleteEntry(re.VaultID); err != nil {
+		m.logger.Printf("[ERR] expire: failed to delete lease entry for '%s': %v", re.VaultID, err)
+	}
+	if err := m.deleteRevocation(re.VaultID); err != nil {
+		m.logger.Printf("[ERR] expire: failed to clear revocation queue entry for '%s': %v", re.VaultID, err)
+	}
+	m.logger.Printf("[INFO] expire: revoked queued entry '%s'", re.VaultID)
+}
+
+// persistRevocation writes a revocationEntry to the durable queue.
+func (m *ExpirationManager) persistRevocation(re *revocationEntry) error {
+	buf, err := json.Marshal(re)
+	if err != nil {
+		return fmt.Errorf("failed to encode revocation queue entry: %v", err)
+	}
+	ent := logical.StorageEntry{
+		Key:   re.VaultID,
+		Value: buf,
+	}
+	if err := m.revokeView.Put(&ent); err != nil {
+		return fmt.Errorf("failed to persist revocation queue entry: %v", err)
+	}
+	return nil
+}
+
+// loadRevocation reads a single revocationEntry from the durable
+// queue, returning nil if vaultID has no queued revocation.
+func (m *ExpirationManager) loadRevocation(vaultID string) (*revocationEntry, error) {
+	out, err := m.revokeView.Get(vaultID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read revocation queue entry: %v", err)
+	}
+	if out == nil {
+		return nil, nil
+	}
+	re := new(revocationEntry)
+	if err := json.Unmarshal(out.Value, re); err != nil {
+		return nil, fmt.Errorf("failed to decode revocation queue entry: %v", err)
+	}
+	return re, nil
+}
+
+// listRevocations returns every entry currently in the durable
+// revocation queue.
+func (m *ExpirationManager) listRevocations() ([]*revocationEntry, error) {
+	keys, err := CollectKeys(m.revokeView)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan revocation queue: %v", err)
+	}
+	out := make([]*revocationEntry, 0, len(keys))
+	for _, vaultID := range keys {
+		re, err := m.loadRevocation(vaultID)
+		if err != nil {
+			return nil, err
+		}
+		if re == nil {
+			continue
+		}
+		out = append(out, re)
+	}
+	return out, nil
+}
+
+// deleteRevocation removes a revocationEntry from the durable queue.
+func (m *ExpirationManager) deleteRevocation(vaultID string) error {
+	if err := m.revokeView.Delete(vaultID); err != nil {
+		return fmt.Errorf("failed to delete revocation queue entry: %v", err)
+	}
+	return nil
 }
 
 // revokeEntry is used to attempt revocation of an internal entry
@@ -402,6 +932,17 @@ func (m *ExpirationManager) revokeEntry(le *leaseEntry) error {
 
 // renewEntry is used to attempt renew of an internal entry
 func (m *ExpirationManager) renewEntry(le *leaseEntry, increment time.Duration) (*logical.Response, error) {
+	if le.Auth != nil {
+		auth := *le.Auth
+		auth.TTL = increment
+
+		resp, err := m.router.Route(logical.RenewAuthRequest(le.Path, &auth, le.Data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to renew entry: %v", err)
+		}
+		return resp, nil
+	}
+
 	secret := *le.Secret
 	secret.LeaseIncrement = increment
 	secret.VaultID = ""
@@ -416,18 +957,84 @@ func (m *ExpirationManager) renewEntry(le *leaseEntry, increment time.Duration)
 
 // loadEntry is used to read a lease entry
 func (m *ExpirationManager) loadEntry(vaultID string) (*leaseEntry, error) {
+	le, _, err := m.loadEntryEnvelope(vaultID)
+	return le, err
+}
+
+// sealedLeaseEntry is the on-disk envelope wrapping a lease entry's
+// JSON payload once it has passed through the configured EntryCipher.
+// KeyVersion lets Restore detect entries sealed under a stale key and
+// re-wrap them when the cipher rotates.
+type sealedLeaseEntry struct {
+	KeyVersion int    `json:"key_version"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// entryCipherKeyVersion reports the version the configured EntryCipher
+// currently seals under, or 0 if it doesn't support rotation.
+func (m *ExpirationManager) entryCipherKeyVersion() int {
+	if vc, ok := m.config.Cipher.(VersionedEntryCipher); ok {
+		return vc.KeyVersion()
+	}
+	return 0
+}
+
+// loadEntryEnvelope reads and decrypts a lease entry, also returning
+// the key version it was sealed under so Restore can detect stale
+// entries. It transparently reads entries written before EntryCipher
+// existed, which are plain leaseEntry JSON rather than a
+// sealedLeaseEntry envelope; those are reported as key version 0, so
+// Restore re-wraps them only when the configured cipher reports a
+// nonzero key version. Under a cipher without versions (key version 0),
+// Restore leaves them in the legacy format.
+func (m *ExpirationManager) loadEntryEnvelope(vaultID string) (*leaseEntry, int, error) {
 	out, err := m.view.Get(vaultID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read lease entry: %v", err)
+		return nil, 0, fmt.Errorf("failed to read lease entry: %v", err)
 	}
 	if out == nil {
-		return nil, nil
+		return nil, 0, nil
+	}
+
+	if le, ok := decodeLegacyLeaseEntry(out.Value); ok {
+		return le, 0, nil
+	}
+
+	var env sealedLeaseEntry
+	if err := json.Unmarshal(out.Value, &env); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode lease entry envelope: %v", err)
+	}
+
+	plaintext, err := m.config.Cipher.Open(env.Ciphertext)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to open lease entry: %v", err)
+	}
+
+	le, err := decodeLeaseEntry(plaintext)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to decode lease entry: %v", err)
+	}
+	return le, env.KeyVersion, nil
+}
+
+// decodeLegacyLeaseEntry detects and decodes the pre-EntryCipher
+// on-disk format: plain leaseEntry JSON, keyed by its top-level
+// "vault_id" field, rather than a sealedLeaseEntry envelope keyed by
+// "key_version"/"ciphertext". The second return value is false if buf
+// isn't in the legacy format.
+func decodeLegacyLeaseEntry(buf []byte) (*leaseEntry, bool) {
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(buf, &probe); err != nil {
+		return nil, false
 	}
-	le, err := decodeLeaseEntry(out.Value)
+	if _, ok := probe["vault_id"]; !ok {
+		return nil, false
+	}
+	le, err := decodeLeaseEntry(buf)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode lease entry: %v", err)
+		return nil, false
 	}
-	return le, nil
+	return le, true
 }
 
 // persistEntry is used to persist a lease entry
@@ -438,10 +1045,25 @@ func (m *ExpirationManager) persistEntry(le *leaseEntry) error {
 		return fmt.Errorf("failed to encode lease entry: %v", err)
 	}
 
+	// Seal it with the configured EntryCipher (a no-op by default)
+	ciphertext, err := m.config.Cipher.Seal(buf)
+	if err != nil {
+		return fmt.Errorf("failed to seal lease entry: %v", err)
+	}
+
+	env := sealedLeaseEntry{
+		KeyVersion: m.entryCipherKeyVersion(),
+		Ciphertext: ciphertext,
+	}
+	envBuf, err := json.Marshal(&env)
+	if err != nil {
+		return fmt.Errorf("failed to encode lease entry envelope: %v", err)
+	}
+
 	// Write out to the view
 	ent := logical.StorageEntry{
 		Key:   le.VaultID,
-		Value: buf,
+		Value: envBuf,
 	}
 	if err := m.view.Put(&ent); err != nil {
 		return fmt.Errorf("failed to persist lease entry: %v", err)
@@ -460,13 +1082,22 @@ func (m *ExpirationManager) deleteEntry(vaultID string) error {
 // leaseEntry is used to structure the values the expiration
 // manager stores. This is used to handle renew and revocation.
 type leaseEntry struct {
-	VaultID    string                 `json:"vault_id"`
-	LoginToken string                 `json:"login_token"`
-	Path       string                 `json:"path"`
-	Data       map[string]interface{} `json:"data"`
-	Secret     *logical.Secret        `json:"secret"`
-	IssueTime  time.Time              `json:"issue_time"`
-	ExpireTime time.Time              `json:"expire_time"`
+	VaultID     string                 `json:"vault_id"`
+	LoginToken  string                 `json:"login_token"`
+	Path        string                 `json:"path"`
+	Data        map[string]interface{} `json:"data"`
+	Secret      *logical.Secret        `json:"secret"`
+	Auth        *logical.Auth          `json:"auth"`
+	IssueTime   time.Time              `json:"issue_time"`
+	ExpireTime  time.Time              `json:"expire_time"`
+	LastRenewed time.Time              `json:"last_renewed"`
+
+	// Period holds a periodic auth's renewal interval and is only ever
+	// populated by RegisterAuth for entries with Auth set. When set,
+	// Renew ignores the caller-supplied increment and always extends
+	// ExpireTime to now + Period instead of letting the lease count
+	// down to a fixed expiry.
+	Period time.Duration `json:"period"`
 }
 
 // encode is used to JSON encode the lease entry
@@ -0,0 +1,92 @@
+package vault
+
+import (
+	"testing"
+	"time"
+)
+
+// newTestExpirationManager builds an ExpirationManager with just enough
+// state to exercise config-driven logic (jitter, backoff) that doesn't
+// touch the barrier/router, which have no fakes available in this tree.
+func newTestExpirationManager(config *ExpirationConfig) *ExpirationManager {
+	return &ExpirationManager{
+		config: normalizedExpirationConfig(config),
+	}
+}
+
+// TestExpirationManager_Jitter verifies that jittering a batch of
+// identical expiration durations (as Restore does for leases with the
+// same expiry) spreads the fire times across the configured jitter
+// window, rather than all firing at once.
+func TestExpirationManager_Jitter(t *testing.T) {
+	window := 100 * time.Millisecond
+	m := newTestExpirationManager(&ExpirationConfig{LeaseJitter: window})
+
+	base := 5 * time.Second
+	seen := make(map[time.Duration]bool)
+	for i := 0; i < 50; i++ {
+		d := m.jitter(base)
+		if d < base {
+			t.Fatalf("jittered duration %v is shorter than base %v", d, base)
+		}
+		if d >= base+window {
+			t.Fatalf("jittered duration %v exceeds base+window %v", d, base+window)
+		}
+		seen[d] = true
+	}
+	if len(seen) < 2 {
+		t.Fatalf("expected jitter to spread fire times across the window, got %d distinct values", len(seen))
+	}
+}
+
+// TestExpirationManager_JitterDisabled verifies that a negative
+// LeaseJitter disables jitter entirely, so operators can opt out of the
+// spread. A zero LeaseJitter is left unset by normalizedExpirationConfig
+// and falls back to defaultLeaseJitter instead; see
+// TestNormalizedExpirationConfig_LeaseJitterZeroIsUnset.
+func TestExpirationManager_JitterDisabled(t *testing.T) {
+	m := newTestExpirationManager(&ExpirationConfig{LeaseJitter: -1})
+	base := 5 * time.Second
+	if d := m.jitter(base); d != base {
+		t.Fatalf("expected jitter to be a no-op when disabled, got %v", d)
+	}
+}
+
+// TestNormalizedExpirationConfig_LeaseJitterZeroIsUnset documents that a
+// zero LeaseJitter is treated as "not set" and normalized to
+// defaultLeaseJitter, per the documented contract on
+// ExpirationConfig.LeaseJitter ("set to a negative value to disable
+// jitter entirely").
+func TestNormalizedExpirationConfig_LeaseJitterZeroIsUnset(t *testing.T) {
+	out := normalizedExpirationConfig(&ExpirationConfig{LeaseJitter: 0})
+	if out.LeaseJitter != defaultLeaseJitter {
+		t.Fatalf("expected LeaseJitter: 0 to normalize to defaultLeaseJitter (%v), got %v", defaultLeaseJitter, out.LeaseJitter)
+	}
+}
+
+// TestExpirationManager_RevokeBackoff verifies that revoke retry delays
+// grow exponentially with attempt count but are capped once attempt
+// exceeds MaxRevokeAttempts.
+func TestExpirationManager_RevokeBackoff(t *testing.T) {
+	m := newTestExpirationManager(&ExpirationConfig{
+		RevokeRetryBase:   1 * time.Second,
+		MaxRevokeAttempts: 4,
+		LeaseJitter:       -1,
+	})
+
+	prev := time.Duration(0)
+	for attempt := uint(0); attempt < 4; attempt++ {
+		d := m.revokeBackoff(attempt)
+		if d <= prev {
+			t.Fatalf("expected revokeBackoff to grow with attempt %d, got %v after %v", attempt, d, prev)
+		}
+		prev = d
+	}
+
+	// Once attempt exceeds MaxRevokeAttempts, the delay should stop
+	// growing.
+	capped := m.revokeBackoff(4)
+	if d := m.revokeBackoff(10); d != capped {
+		t.Fatalf("expected revokeBackoff to cap at MaxRevokeAttempts, got %v vs capped %v", d, capped)
+	}
+}
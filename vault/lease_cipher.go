@@ -0,0 +1,174 @@
+package vault
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// EntryCipher is used by ExpirationManager to add a layer of envelope
+// encryption around a lease entry's JSON payload before it is written
+// past the barrier. This protects secret material embedded in
+// le.Data/le.Secret even if the outer barrier key is ever
+// compromised. The default is a no-op; a different EntryCipher (e.g. one
+// backed by transit or a cloud KMS) is set through ExpirationConfig.
+// That single cipher seals every lease the ExpirationManager persists;
+// there is no per-mount choice of cipher.
+//
+// Known-unwired scaffolding: since setupExpiration always constructs
+// its ExpirationManager with a nil ExpirationConfig (see the TODO
+// there), there is currently no way for an operator to supply a
+// non-default EntryCipher; TransitEntryCipher below has no callers in
+// this tree yet.
+type EntryCipher interface {
+	// Seal encrypts plaintext, returning an opaque blob suitable for
+	// storage.
+	Seal(plaintext []byte) ([]byte, error)
+
+	// Open decrypts a blob previously returned by Seal.
+	Open(ciphertext []byte) ([]byte, error)
+}
+
+// VersionedEntryCipher is implemented by ciphers whose underlying key
+// can rotate. ExpirationManager uses KeyVersion, when available, to
+// detect entries sealed under a stale key during Restore and re-wrap
+// them under the current one.
+type VersionedEntryCipher interface {
+	EntryCipher
+
+	// KeyVersion returns the version of the key currently used by
+	// Seal.
+	KeyVersion() int
+}
+
+// noopEntryCipher is the default EntryCipher: it leaves lease entries
+// protected only by the outer barrier, exactly as before EntryCipher
+// existed.
+type noopEntryCipher struct{}
+
+func (noopEntryCipher) Seal(plaintext []byte) ([]byte, error) { return plaintext, nil }
+func (noopEntryCipher) Open(ciphertext []byte) ([]byte, error) { return ciphertext, nil }
+
+// KMS is the minimal interface a TransitEntryCipher needs to
+// wrap/unwrap the per-entry data key it generates. An implementation
+// might call Vault's own transit backend, a cloud KMS, or an HSM.
+type KMS interface {
+	// WrapKey encrypts a data encryption key under the KMS's current
+	// key, returning the wrapped key and that key's version.
+	WrapKey(dek []byte) (wrapped []byte, keyVersion int, err error)
+
+	// UnwrapKey decrypts a key previously returned by WrapKey. The
+	// KMS must be able to unwrap keys wrapped under prior versions of
+	// its key, not only the current one.
+	UnwrapKey(wrapped []byte, keyVersion int) (dek []byte, err error)
+
+	// CurrentKeyVersion reports the version WrapKey will use.
+	CurrentKeyVersion() int
+}
+
+// transitEnvelope is the on-disk shape produced by TransitEntryCipher.
+type transitEnvelope struct {
+	WrappedDEK []byte `json:"wrapped_dek"`
+	KeyVersion int    `json:"key_version"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// TransitEntryCipher is an EntryCipher that performs KMS-backed
+// envelope encryption: each Seal generates a fresh AES-256 data
+// encryption key, encrypts the plaintext with AES-GCM, and wraps the
+// data key with the configured KMS rather than encrypting the payload
+// with the KMS key directly.
+type TransitEntryCipher struct {
+	kms KMS
+}
+
+// NewTransitEntryCipher creates a TransitEntryCipher backed by kms.
+func NewTransitEntryCipher(kms KMS) *TransitEntryCipher {
+	return &TransitEntryCipher{kms: kms}
+}
+
+// Seal implements EntryCipher.
+func (c *TransitEntryCipher) Seal(plaintext []byte) ([]byte, error) {
+	dek := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, dek); err != nil {
+		return nil, fmt.Errorf("failed to generate data key: %v", err)
+	}
+
+	gcm, err := newGCM(dek)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %v", err)
+	}
+
+	wrapped, keyVersion, err := c.kms.WrapKey(dek)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap data key: %v", err)
+	}
+
+	env := transitEnvelope{
+		WrappedDEK: wrapped,
+		KeyVersion: keyVersion,
+		Nonce:      nonce,
+		Ciphertext: gcm.Seal(nil, nonce, plaintext, nil),
+	}
+	buf, err := json.Marshal(&env)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode envelope: %v", err)
+	}
+	return buf, nil
+}
+
+// Open implements EntryCipher.
+func (c *TransitEntryCipher) Open(ciphertext []byte) ([]byte, error) {
+	var env transitEnvelope
+	if err := json.Unmarshal(ciphertext, &env); err != nil {
+		return nil, fmt.Errorf("failed to decode envelope: %v", err)
+	}
+
+	dek, err := c.kms.UnwrapKey(env.WrappedDEK, env.KeyVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key: %v", err)
+	}
+
+	gcm, err := newGCM(dek)
+	if err != nil {
+		return nil, err
+	}
+	if len(env.Nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("invalid nonce size in envelope")
+	}
+
+	plaintext, err := gcm.Open(nil, env.Nonce, env.Ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt entry: %v", err)
+	}
+	return plaintext, nil
+}
+
+// KeyVersion implements VersionedEntryCipher, reporting the wrapping
+// key version so ExpirationManager.Restore can re-wrap entries sealed
+// under an older KMS key.
+func (c *TransitEntryCipher) KeyVersion() int {
+	return c.kms.CurrentKeyVersion()
+}
+
+// newGCM builds an AES-GCM AEAD around a raw key.
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %v", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %v", err)
+	}
+	return gcm, nil
+}
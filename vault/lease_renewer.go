@@ -0,0 +1,190 @@
+package vault
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/vault/logical"
+)
+
+// RenewOutput is sent on a LeaseRenewer's RenewCh each time the managed
+// lease is successfully renewed.
+type RenewOutput struct {
+	// RenewedAt is when the renewal completed.
+	RenewedAt time.Time
+
+	// Resp is the response returned by the renewal.
+	Resp *logical.Response
+}
+
+// LeaseRenewer manages the background renewal of a single lease. It
+// mirrors the renewer loop pattern used by Vault clients (e.g.
+// consul-template): start the renewer, receive renewal events on
+// RenewCh, and stop on lease expiry, a non-renewable response, or an
+// explicit Stop.
+//
+// Internal subsystems that would otherwise open-code a renewal timer
+// (the approle pathLoginRenew path, future auto-renewing mounts) can
+// delegate lease lifecycle management to a LeaseRenewer instead.
+type LeaseRenewer struct {
+	exp       *ExpirationManager
+	vaultID   string
+	increment time.Duration
+
+	renewCh chan *RenewOutput
+	doneCh  chan error
+
+	stopLock sync.Mutex
+	stopCh   chan struct{}
+	stopped  bool
+}
+
+// NewLeaseRenewer creates a LeaseRenewer for the lease named by vaultID.
+// increment is passed through to ExpirationManager.Renew on each
+// renewal attempt.
+//
+// Known-unwired scaffolding: nothing in this tree calls NewLeaseRenewer
+// yet; it's intended for the internal subsystems named above to adopt
+// once they're ready to delegate their renewal timers to it.
+func NewLeaseRenewer(exp *ExpirationManager, vaultID string, increment time.Duration) *LeaseRenewer {
+	r := &LeaseRenewer{
+		exp:       exp,
+		vaultID:   vaultID,
+		increment: increment,
+		renewCh:   make(chan *RenewOutput),
+		doneCh:    make(chan error, 1),
+		stopCh:    make(chan struct{}),
+	}
+	exp.trackRenewer(r)
+	return r
+}
+
+// RenewCh returns the channel on which successful renewals are
+// reported.
+func (r *LeaseRenewer) RenewCh() <-chan *RenewOutput {
+	return r.renewCh
+}
+
+// DoneCh returns the channel on which the renewer reports its terminal
+// error (nil on a clean stop, non-nil if the lease expired, failed to
+// renew, or came back non-renewable).
+func (r *LeaseRenewer) DoneCh() <-chan error {
+	return r.doneCh
+}
+
+// Renew starts the background renewal loop. It returns immediately;
+// outcomes are reported on RenewCh/DoneCh.
+func (r *LeaseRenewer) Renew() {
+	go r.run()
+}
+
+// Stop halts the renewal loop. It is safe to call multiple times and
+// is called automatically when the underlying lease is revoked.
+func (r *LeaseRenewer) Stop() {
+	r.stopLock.Lock()
+	defer r.stopLock.Unlock()
+	if !r.stopped {
+		r.stopped = true
+		close(r.stopCh)
+	}
+}
+
+// run is the renewer loop: sleep until ~2/3 of the lease TTL has
+// elapsed, renew, and repeat until the lease stops being renewable.
+// Leases backed by a Secret (ordinary mount leases) and leases backed
+// by an Auth (e.g. the approle pathLoginRenew path, via RegisterAuth)
+// are both supported.
+func (r *LeaseRenewer) run() {
+	defer r.exp.untrackRenewer(r)
+
+	for {
+		le, err := r.exp.loadEntry(r.vaultID)
+		if err != nil {
+			r.finish(err)
+			return
+		}
+		if le == nil {
+			r.finish(fmt.Errorf("lease '%s' not found", r.vaultID))
+			return
+		}
+		if le.Secret == nil && le.Auth == nil {
+			r.finish(fmt.Errorf("lease '%s' has no secret or auth", r.vaultID))
+			return
+		}
+
+		if le.ExpireTime.IsZero() {
+			r.finish(fmt.Errorf("lease '%s' does not expire", r.vaultID))
+			return
+		}
+
+		// Renew at ~2/3 of the lease TTL, i.e. when a third of the
+		// remaining window is left before ExpireTime.
+		renewAt := le.ExpireTime.Add(-leaseRenewalTotal(le) / 3)
+		wait := renewAt.Sub(time.Now().UTC())
+		if wait < 0 {
+			wait = 0
+		}
+
+		select {
+		case <-time.After(wait):
+		case <-r.stopCh:
+			r.finish(nil)
+			return
+		}
+
+		resp, err := r.exp.Renew(r.vaultID, r.increment)
+		if err != nil {
+			r.finish(err)
+			return
+		}
+		if !isRenewableResponse(resp) {
+			r.finish(fmt.Errorf("lease '%s' is no longer renewable", r.vaultID))
+			return
+		}
+
+		select {
+		case r.renewCh <- &RenewOutput{RenewedAt: time.Now().UTC(), Resp: resp}:
+		case <-r.stopCh:
+			r.finish(nil)
+			return
+		}
+	}
+}
+
+// leaseRenewalTotal returns the lease window used to schedule the next
+// renewal: the Secret's Lease+LeaseGracePeriod for Secret-backed
+// leases, or the Auth's Period (falling back to its TTL) for
+// Auth-backed leases.
+func leaseRenewalTotal(le *leaseEntry) time.Duration {
+	if le.Secret != nil {
+		return le.Secret.Lease + le.Secret.LeaseGracePeriod
+	}
+	if le.Auth.Period > 0 {
+		return le.Auth.Period
+	}
+	return le.Auth.TTL
+}
+
+// isRenewableResponse reports whether resp represents a successful,
+// still-renewable renewal of either a Secret-backed or Auth-backed
+// lease.
+func isRenewableResponse(resp *logical.Response) bool {
+	switch {
+	case resp == nil:
+		return false
+	case resp.Secret != nil:
+		return resp.Secret.Renewable
+	case resp.Auth != nil:
+		return resp.Auth.Renewable
+	default:
+		return false
+	}
+}
+
+// finish reports the terminal error on doneCh, stopping the renewer if
+// it hasn't already been stopped.
+func (r *LeaseRenewer) finish(err error) {
+	r.Stop()
+	r.doneCh <- err
+}
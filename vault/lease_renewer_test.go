@@ -0,0 +1,71 @@
+package vault
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/vault/logical"
+)
+
+// TestLeaseRenewalTotal_Secret verifies that a Secret-backed lease
+// schedules its next renewal off the secret's lease window.
+func TestLeaseRenewalTotal_Secret(t *testing.T) {
+	le := &leaseEntry{
+		Secret: &logical.Secret{
+			Lease:            10 * time.Second,
+			LeaseGracePeriod: 2 * time.Second,
+		},
+	}
+	if got, want := leaseRenewalTotal(le), 12*time.Second; got != want {
+		t.Fatalf("leaseRenewalTotal() = %v, want %v", got, want)
+	}
+}
+
+// TestLeaseRenewalTotal_Auth verifies that an Auth-backed periodic
+// lease (as registered by RegisterAuth for the approle pathLoginRenew
+// flow) schedules off Period when set, and falls back to TTL
+// otherwise.
+func TestLeaseRenewalTotal_Auth(t *testing.T) {
+	periodic := &leaseEntry{
+		Auth: &logical.Auth{
+			TTL:    5 * time.Second,
+			Period: 30 * time.Second,
+		},
+	}
+	if got, want := leaseRenewalTotal(periodic), 30*time.Second; got != want {
+		t.Fatalf("leaseRenewalTotal() = %v, want %v", got, want)
+	}
+
+	nonPeriodic := &leaseEntry{
+		Auth: &logical.Auth{
+			TTL: 5 * time.Second,
+		},
+	}
+	if got, want := leaseRenewalTotal(nonPeriodic), 5*time.Second; got != want {
+		t.Fatalf("leaseRenewalTotal() = %v, want %v", got, want)
+	}
+}
+
+// TestIsRenewableResponse verifies renewability detection for both
+// Secret and Auth renewal responses, and that a reauthentication
+// attempt (a response with neither) is treated as non-renewable so the
+// renewer stops rather than looping forever mid-renewal.
+func TestIsRenewableResponse(t *testing.T) {
+	cases := []struct {
+		name string
+		resp *logical.Response
+		want bool
+	}{
+		{"nil response", nil, false},
+		{"renewable secret", &logical.Response{Secret: &logical.Secret{Renewable: true}}, true},
+		{"non-renewable secret", &logical.Response{Secret: &logical.Secret{Renewable: false}}, false},
+		{"renewable auth", &logical.Response{Auth: &logical.Auth{Renewable: true}}, true},
+		{"non-renewable auth", &logical.Response{Auth: &logical.Auth{Renewable: false}}, false},
+		{"neither secret nor auth", &logical.Response{}, false},
+	}
+	for _, tc := range cases {
+		if got := isRenewableResponse(tc.resp); got != tc.want {
+			t.Errorf("%s: isRenewableResponse() = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}